@@ -0,0 +1,195 @@
+package statsd
+
+import (
+	"sync"
+	"time"
+)
+
+// aggregatorShards is the number of independent, separately-locked maps
+// used for each metric type. Sharding keeps lock contention low when many
+// goroutines submit metrics concurrently.
+const aggregatorShards = 32
+
+type aggKey struct {
+	prefix string
+	bucket string
+	tags   string
+}
+
+type counterShard struct {
+	mu sync.Mutex
+	m  map[aggKey]float64
+}
+
+type gaugeShard struct {
+	mu sync.Mutex
+	m  map[aggKey]interface{}
+}
+
+type setShard struct {
+	mu sync.Mutex
+	m  map[aggKey]map[string]struct{}
+}
+
+// An aggregator accumulates Count, Gauge and Unique calls in memory and
+// flushes them to a Client's conn on a timer. Counters are summed, gauges
+// keep their last value, and sets deduplicate their members.
+type aggregator struct {
+	c *Client
+
+	counters [aggregatorShards]counterShard
+	gauges   [aggregatorShards]gaugeShard
+	sets     [aggregatorShards]setShard
+
+	stop chan struct{}
+}
+
+func newAggregator(c *Client) *aggregator {
+	a := &aggregator{c: c, stop: make(chan struct{})}
+	for i := range a.counters {
+		a.counters[i].m = make(map[aggKey]float64)
+	}
+	for i := range a.gauges {
+		a.gauges[i].m = make(map[aggKey]interface{})
+	}
+	for i := range a.sets {
+		a.sets[i].m = make(map[aggKey]map[string]struct{})
+	}
+	return a
+}
+
+func (a *aggregator) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// shard picks the shard a bucket's entries live in, so that unrelated
+// buckets rarely contend on the same mutex.
+func shard(bucket string) int {
+	const prime32 = 16777619
+	h := uint32(2166136261)
+	for i := 0; i < len(bucket); i++ {
+		h *= prime32
+		h ^= uint32(bucket[i])
+	}
+	return int(h % aggregatorShards)
+}
+
+func (a *aggregator) count(prefix, bucket string, n float64, tags string) {
+	s := &a.counters[shard(bucket)]
+	key := aggKey{prefix, bucket, tags}
+
+	s.mu.Lock()
+	s.m[key] += n
+	s.mu.Unlock()
+}
+
+func (a *aggregator) gauge(prefix, bucket string, value interface{}, tags string) {
+	s := &a.gauges[shard(bucket)]
+	key := aggKey{prefix, bucket, tags}
+
+	s.mu.Lock()
+	s.m[key] = value
+	s.mu.Unlock()
+}
+
+func (a *aggregator) unique(prefix, bucket, value, tags string) {
+	s := &a.sets[shard(bucket)]
+	key := aggKey{prefix, bucket, tags}
+
+	s.mu.Lock()
+	members, ok := s.m[key]
+	if !ok {
+		members = make(map[string]struct{})
+		s.m[key] = members
+	}
+	members[value] = struct{}{}
+	s.mu.Unlock()
+}
+
+// flush sends every accumulated counter, gauge and set to the Client's conn
+// and resets the aggregator's state.
+func (a *aggregator) flush() {
+	a.c.conn.mu.Lock()
+	defer a.c.conn.mu.Unlock()
+
+	for i := range a.counters {
+		s := &a.counters[i]
+		s.mu.Lock()
+		for k, v := range s.m {
+			a.c.conn.metricLocked(k.prefix, k.bucket, v, "c", 1, k.tags)
+			delete(s.m, k)
+		}
+		s.mu.Unlock()
+	}
+
+	for i := range a.gauges {
+		s := &a.gauges[i]
+		s.mu.Lock()
+		for k, v := range s.m {
+			// Gauges cannot be negative on the wire: a negative value is
+			// reported by first resetting the gauge to 0, matching
+			// conn.gauge's non-aggregated behavior.
+			if isNegative(v) {
+				a.c.conn.metricLocked(k.prefix, k.bucket, 0, "g", 1, "")
+			}
+			a.c.conn.metricLocked(k.prefix, k.bucket, v, "g", 1, k.tags)
+			delete(s.m, k)
+		}
+		s.mu.Unlock()
+	}
+
+	for i := range a.sets {
+		s := &a.sets[i]
+		s.mu.Lock()
+		for k, members := range s.m {
+			for member := range members {
+				a.c.conn.metricLocked(k.prefix, k.bucket, member, "s", 1, k.tags)
+			}
+			delete(s.m, k)
+		}
+		s.mu.Unlock()
+	}
+
+	a.c.conn.flush(0)
+}
+
+// scaleCount applies the inverse of a sample rate to a counter value, so
+// that a sampled counter still aggregates to the right total.
+func scaleCount(n interface{}, rate float32) float64 {
+	v := toFloat64(n)
+	if rate > 0 && rate < 1 {
+		v /= float64(rate)
+	}
+	return v
+}
+
+func toFloat64(n interface{}) float64 {
+	switch v := n.(type) {
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return 0
+	}
+}
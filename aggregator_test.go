@@ -0,0 +1,47 @@
+package statsd
+
+import "testing"
+
+func TestAggregationKeysByCallerPrefix(t *testing.T) {
+	base, buf := newTestClient(Datadog)
+	base.aggregator = newAggregator(base)
+
+	svc := base.Clone()
+	svc.prefix = "svc."
+
+	svc.Count("requests", 1)
+	base.Count("requests", 1)
+	base.Flush()
+
+	got := buf.String()
+	wantBase := "requests:1|c\n"
+	wantSvc := "svc.requests:1|c\n"
+	if !containsLine(got, wantBase) {
+		t.Errorf("missing base counter in flush output: %q", got)
+	}
+	if !containsLine(got, wantSvc) {
+		t.Errorf("missing svc-prefixed counter in flush output: %q", got)
+	}
+}
+
+func TestAggregatedNegativeGaugeIsReset(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+	c.aggregator = newAggregator(c)
+
+	c.Gauge("temp", -5)
+	c.Flush()
+
+	want := "temp:0|g\ntemp:-5|g\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func containsLine(haystack, line string) bool {
+	for i := 0; i+len(line) <= len(haystack); i++ {
+		if haystack[i:i+len(line)] == line {
+			return true
+		}
+	}
+	return false
+}
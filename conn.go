@@ -0,0 +1,188 @@
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A conn holds the buffer and network connection used by a Client to send
+// metrics to a StatsD daemon. Access to a conn must be synchronized by the
+// caller: all of Client's methods lock conn.mu before using it.
+type conn struct {
+	mu  sync.Mutex
+	w   io.WriteCloser
+	buf []byte
+
+	network       string
+	maxPacketSize int
+	tagFormat     TagFormat
+	errorHandler  func(error)
+	closed        bool
+
+	// Telemetry counters. They are only ever touched with atomic
+	// operations so they can be read by the telemetry goroutine without
+	// holding mu.
+	metricsCount         int64
+	eventsCount          int64
+	serviceChecksCount   int64
+	bytesSent            int64
+	bytesDropped         int64
+	packetsSent          int64
+	packetsDroppedQueue  int64
+	packetsDroppedWriter int64
+}
+
+func newConn(conf connConfig, muted bool) (*conn, error) {
+	if muted {
+		return &conn{}, nil
+	}
+
+	w, err := net.Dial(conf.Network, conf.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &conn{
+		w:             w,
+		network:       conf.Network,
+		maxPacketSize: conf.MaxPacketSize,
+		tagFormat:     conf.TagFormat,
+		errorHandler:  conf.ErrorHandler,
+	}
+
+	if conf.FlushPeriod > 0 {
+		go c.flushLoop(conf.FlushPeriod)
+	}
+
+	return c, nil
+}
+
+func (c *conn) flushLoop(flushPeriod time.Duration) {
+	ticker := time.NewTicker(flushPeriod)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+		c.flush(0)
+		c.mu.Unlock()
+	}
+}
+
+// write appends a single datagram to the buffer, flushing first if it would
+// not otherwise fit within maxPacketSize.
+func (c *conn) write(p []byte) {
+	if c.w == nil {
+		atomic.AddInt64(&c.packetsDroppedQueue, 1)
+		atomic.AddInt64(&c.bytesDropped, int64(len(p)))
+		return
+	}
+	if c.maxPacketSize != 0 && len(c.buf) > 0 && len(c.buf)+len(p) > c.maxPacketSize {
+		c.flush(len(p))
+	}
+	c.buf = append(c.buf, p...)
+}
+
+// flush writes the buffer to the underlying connection. allocSize is used as
+// a hint for the capacity of the buffer that replaces it.
+func (c *conn) flush(allocSize int) {
+	if len(c.buf) == 0 {
+		return
+	}
+
+	n, err := c.w.Write(c.buf)
+	if err == nil {
+		atomic.AddInt64(&c.packetsSent, 1)
+		atomic.AddInt64(&c.bytesSent, int64(n))
+	} else {
+		atomic.AddInt64(&c.bytesDropped, int64(len(c.buf)))
+	}
+	c.handleError(err)
+
+	if allocSize > cap(c.buf) {
+		c.buf = make([]byte, 0, allocSize)
+	} else {
+		c.buf = c.buf[:0]
+	}
+}
+
+func (c *conn) handleError(err error) {
+	if err == nil {
+		return
+	}
+	atomic.AddInt64(&c.packetsDroppedWriter, 1)
+	if c.errorHandler != nil {
+		c.errorHandler(err)
+	}
+}
+
+// metric locks the conn and writes a single metric datagram. It is used by
+// Client's methods, which do not hold c.mu themselves.
+func (c *conn) metric(prefix, bucket string, value interface{}, typ string, rate float32, tags string) {
+	c.mu.Lock()
+	c.metricLocked(prefix, bucket, value, typ, rate, tags)
+	c.mu.Unlock()
+}
+
+// metricLocked is the locked counterpart of metric, used by callers (such
+// as the aggregator) that already hold c.mu.
+func (c *conn) metricLocked(prefix, bucket string, value interface{}, typ string, rate float32, tags string) {
+	atomic.AddInt64(&c.metricsCount, 1)
+
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.WriteString(bucket)
+	buf.WriteByte(':')
+	fmt.Fprintf(&buf, "%v", value)
+	buf.WriteByte('|')
+	buf.WriteString(typ)
+	if rate < 1 {
+		fmt.Fprintf(&buf, "|@%g", rate)
+	}
+	buf.WriteString(tags)
+	buf.WriteByte('\n')
+
+	c.write(buf.Bytes())
+}
+
+func (c *conn) gauge(prefix, bucket string, value interface{}, tags string) {
+	// Gauges cannot be negative on the wire: a negative value is reported
+	// by first resetting the gauge to 0, then sending the value as normal.
+	if isNegative(value) {
+		c.metric(prefix, bucket, 0, "g", 1, "")
+	}
+	c.metric(prefix, bucket, value, "g", 1, tags)
+}
+
+func (c *conn) unique(prefix, bucket, value string, tags string) {
+	c.metric(prefix, bucket, value, "s", 1, tags)
+}
+
+func isNegative(value interface{}) bool {
+	switch v := value.(type) {
+	case int:
+		return v < 0
+	case int8:
+		return v < 0
+	case int16:
+		return v < 0
+	case int32:
+		return v < 0
+	case int64:
+		return v < 0
+	case float32:
+		return v < 0
+	case float64:
+		return v < 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// EventPriority is the priority of an Event, as reported to the DogStatsD
+// server.
+type EventPriority string
+
+// Valid values for EventPriority.
+const (
+	PriorityNormal EventPriority = "normal"
+	PriorityLow    EventPriority = "low"
+)
+
+// EventAlertType is the alert type of an Event, as reported to the
+// DogStatsD server.
+type EventAlertType string
+
+// Valid values for EventAlertType.
+const (
+	AlertInfo    EventAlertType = "info"
+	AlertWarning EventAlertType = "warning"
+	AlertError   EventAlertType = "error"
+	AlertSuccess EventAlertType = "success"
+)
+
+// An Event represents a DogStatsD event. Title and Text are required; every
+// other field is optional.
+type Event struct {
+	Title          string
+	Text           string
+	Timestamp      time.Time
+	Hostname       string
+	AggregationKey string
+	Priority       EventPriority
+	SourceTypeName string
+	AlertType      EventAlertType
+	Tags           []string
+
+	// AggregationKeyUsesPrefix prepends the Client's prefix to
+	// AggregationKey, the same way it is prepended to every bucket name
+	// passed to Count, Gauge, etc. It defaults to false so that
+	// AggregationKey can be shared across Clients with different
+	// prefixes.
+	AggregationKeyUsesPrefix bool
+}
+
+// Event sends e to the StatsD daemon as a DogStatsD event datagram. Event
+// requires the Datadog tag format (see TagsFormat) and does nothing if
+// Title or Text is empty, or if Priority/AlertType hold a value other than
+// their respective zero value or one of the constants above.
+//
+// Unlike other metrics, events are never affected by SampleRate.
+func (c *Client) Event(e Event) {
+	if c.muted {
+		return
+	}
+	if e.Title == "" || e.Text == "" {
+		return
+	}
+	if e.Priority != "" && e.Priority != PriorityNormal && e.Priority != PriorityLow {
+		return
+	}
+	if e.AlertType != "" && e.AlertType != AlertInfo && e.AlertType != AlertWarning &&
+		e.AlertType != AlertError && e.AlertType != AlertSuccess {
+		return
+	}
+
+	text := strings.Replace(e.Text, "\n", `\n`, -1)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "_e{%d,%d}:%s|%s", len(e.Title), len(text), e.Title, text)
+
+	if !e.Timestamp.IsZero() {
+		fmt.Fprintf(&buf, "|d:%d", e.Timestamp.Unix())
+	}
+	if e.Hostname != "" {
+		fmt.Fprintf(&buf, "|h:%s", e.Hostname)
+	}
+	if e.AggregationKey != "" {
+		key := e.AggregationKey
+		if e.AggregationKeyUsesPrefix {
+			key = c.prefix + key
+		}
+		fmt.Fprintf(&buf, "|k:%s", key)
+	}
+	if e.Priority != "" {
+		fmt.Fprintf(&buf, "|p:%s", e.Priority)
+	}
+	if e.SourceTypeName != "" {
+		fmt.Fprintf(&buf, "|s:%s", e.SourceTypeName)
+	}
+	if e.AlertType != "" {
+		fmt.Fprintf(&buf, "|t:%s", e.AlertType)
+	}
+
+	buf.WriteString(joinTags(c.conn.tagFormat, mergeTags(c.tags, e.Tags)))
+	buf.WriteString(c.originTag())
+	buf.WriteByte('\n')
+
+	c.conn.mu.Lock()
+	c.conn.write(buf.Bytes())
+	c.conn.mu.Unlock()
+
+	atomic.AddInt64(&c.conn.eventsCount, 1)
+}
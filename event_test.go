@@ -0,0 +1,58 @@
+package statsd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type nopCloserBuffer struct {
+	bytes.Buffer
+}
+
+func (nopCloserBuffer) Close() error { return nil }
+
+func newTestClient(tf TagFormat) (*Client, *nopCloserBuffer) {
+	buf := &nopCloserBuffer{}
+	c := &Client{
+		conn: &conn{
+			w:             buf,
+			maxPacketSize: 1440,
+			tagFormat:     tf,
+		},
+		rate: 1,
+	}
+	return c, buf
+}
+
+func TestEventDatagram(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+
+	c.Event(Event{
+		Title:     "title",
+		Text:      "line one\nline two",
+		Timestamp: time.Unix(1234, 0),
+		Hostname:  "host",
+		Priority:  PriorityLow,
+		AlertType: AlertError,
+		Tags:      []string{"env", "prod"},
+	})
+	c.Flush()
+
+	got := buf.String()
+	want := `_e{5,18}:title|line one\nline two|d:1234|h:host|p:low|t:error|#env:prod` + "\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEventIgnoresInvalidPriority(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+
+	c.Event(Event{Title: "t", Text: "x", Priority: "bogus"})
+	c.Flush()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no datagram for invalid priority, got %q", buf.String())
+	}
+}
@@ -12,10 +12,14 @@ type config struct {
 }
 
 type clientConfig struct {
-	Muted  bool
-	Rate   float32
-	Prefix string
-	Tags   []tag
+	Muted               bool
+	Rate                float32
+	Prefix              string
+	Tags                []tag
+	AggregationInterval time.Duration
+	OriginDetection     bool
+	ContainerID         string
+	TelemetryInterval   time.Duration
 }
 
 type connConfig struct {
@@ -103,6 +107,64 @@ func SampleRate(rate float32) Option {
 	})
 }
 
+// Aggregation enables client-side pre-aggregation of Count, Gauge and
+// Unique metrics. Instead of sending one packet per call, the Client
+// accumulates them in memory and flushes aggregated metrics every interval,
+// which can dramatically reduce packet volume for hot code paths.
+// Histogram, Timing and Distribution are never aggregated since they
+// require per-observation fidelity.
+//
+// By default, aggregation is disabled. This option is ignored in
+// Client.Clone().
+func Aggregation(interval time.Duration) Option {
+	return Option(func(c *config) {
+		c.Client.AggregationInterval = interval
+	})
+}
+
+// OriginDetection enables automatic detection of the container the Client
+// is running in. When enabled, New reads /proc/self/cgroup once to
+// determine the container ID and appends a "|c:<container-id>" field to
+// every metric, event and service check when the Datadog tag format is used
+// (see TagsFormat), which newer Datadog agents use to
+// attribute metrics sent from behind a shared node IP. Detection only runs
+// on Linux; it is silently skipped elsewhere or if no container ID can be
+// found. Use ContainerID to set the ID explicitly instead.
+//
+// By default, origin detection is disabled. This option is ignored in
+// Client.Clone().
+func OriginDetection(enabled bool) Option {
+	return Option(func(c *config) {
+		c.Client.OriginDetection = enabled
+	})
+}
+
+// ContainerID explicitly sets the container ID appended to every metric,
+// event and service check as a "|c:<container-id>" field when the Datadog
+// tag format is used, overriding OriginDetection.
+//
+// This option is ignored in Client.Clone().
+func ContainerID(id string) Option {
+	return Option(func(c *config) {
+		c.Client.ContainerID = id
+	})
+}
+
+// Telemetry enables opt-in self-telemetry: every interval, the Client
+// emits counters describing its own behaviour (metrics, events and service
+// checks submitted, bytes and packets sent or dropped) through its own
+// conn, tagged with "client:go", "client_version" and "client_transport".
+// The packets_dropped_writer counter counts failed writes to the
+// connection, each of which is also passed to ErrorHandler.
+//
+// By default, self-telemetry is disabled. This option is ignored in
+// Client.Clone().
+func Telemetry(interval time.Duration) Option {
+	return Option(func(c *config) {
+		c.Client.TelemetryInterval = interval
+	})
+}
+
 // Prefix appends the prefix that will be used in every bucket name.
 //
 // Note that when used in cloned, the prefix of the parent Client is not
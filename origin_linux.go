@@ -0,0 +1,54 @@
+//go:build linux
+// +build linux
+
+package statsd
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	cgroupV1ContainerRe = regexp.MustCompile(`(?:^|/)docker-([0-9a-f]{64})\.scope$`)
+	cgroupKubepodsRe    = regexp.MustCompile(`(?:^|/)kubepods[^/]*/.*?/([0-9a-f]{64})(?:\.scope)?$`)
+	cgroupV2ContainerRe = regexp.MustCompile(`(?:^|-)([0-9a-f]{64})(?:\.scope)?$`)
+)
+
+// detectContainerID reads /proc/self/cgroup and tries to extract the ID of
+// the container the process is running in. A cgroup path matches when it
+// ends in "docker-<id>.scope", when it lies under a "kubepods" cgroup and
+// ends in the ID, or when it ends in "-<id>" (optionally followed by
+// ".scope"); a plain "/docker/<id>" path is not matched. It returns "" if no
+// container ID could be determined.
+func detectContainerID() string {
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := parseCgroupLine(scanner.Text()); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func parseCgroupLine(line string) string {
+	parts := strings.SplitN(line, ":", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	path := parts[2]
+
+	for _, re := range []*regexp.Regexp{cgroupV1ContainerRe, cgroupKubepodsRe, cgroupV2ContainerRe} {
+		if m := re.FindStringSubmatch(path); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+//go:build linux
+// +build linux
+
+package statsd
+
+import (
+	"strings"
+	"testing"
+)
+
+var testContainerID = strings.Repeat("a1", 32)
+
+func TestParseCgroupLine(t *testing.T) {
+	cases := map[string]string{
+		"1:name=systemd:/docker/" + testContainerID:                    "",
+		"0::/docker-" + testContainerID + ".scope":                     testContainerID,
+		"1:name=systemd:/kubepods/burstable/podabc/" + testContainerID: testContainerID,
+		"0::/system.slice/docker-" + testContainerID + ".scope":        testContainerID,
+		"not a cgroup line": "",
+	}
+	for in, want := range cases {
+		if got := parseCgroupLine(in); got != want {
+			t.Errorf("parseCgroupLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package statsd
+
+// detectContainerID always returns "" on non-Linux platforms: origin
+// detection relies on parsing /proc/self/cgroup, which has no equivalent
+// elsewhere.
+func detectContainerID() string {
+	return ""
+}
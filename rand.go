@@ -0,0 +1,6 @@
+package statsd
+
+import "math/rand"
+
+// randFloat is a variable so that it can be overridden in tests.
+var randFloat = rand.Float32
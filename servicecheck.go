@@ -0,0 +1,115 @@
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ServiceCheckStatus is the status reported by Client.ServiceCheck.
+type ServiceCheckStatus int
+
+// Valid values for ServiceCheckStatus.
+const (
+	OK ServiceCheckStatus = iota
+	Warning
+	Critical
+	Unknown
+)
+
+type serviceCheck struct {
+	timestamp time.Time
+	hostname  string
+	tags      []string
+	message   string
+}
+
+// A ServiceCheckOption configures a service check. It must be used as an
+// argument to Client.ServiceCheck.
+type ServiceCheckOption func(*serviceCheck)
+
+// ServiceCheckTimestamp sets the time of the service check. By default, the
+// current time is used by the DogStatsD server.
+func ServiceCheckTimestamp(t time.Time) ServiceCheckOption {
+	return ServiceCheckOption(func(s *serviceCheck) {
+		s.timestamp = t
+	})
+}
+
+// ServiceCheckHostname sets the hostname reported with the service check.
+func ServiceCheckHostname(hostname string) ServiceCheckOption {
+	return ServiceCheckOption(func(s *serviceCheck) {
+		s.hostname = hostname
+	})
+}
+
+// ServiceCheckTags appends tags to the service check, in addition to the
+// Client's own tags. Tags must be specified as K, V pairs.
+func ServiceCheckTags(tags ...string) ServiceCheckOption {
+	return ServiceCheckOption(func(s *serviceCheck) {
+		s.tags = tags
+	})
+}
+
+// ServiceCheckMessage sets the message reported with the service check. The
+// message must be the last field of the datagram; it is escaped so it
+// cannot be mistaken for another field.
+func ServiceCheckMessage(message string) ServiceCheckOption {
+	return ServiceCheckOption(func(s *serviceCheck) {
+		s.message = message
+	})
+}
+
+// ServiceCheck sends a service check with the given name and status to the
+// StatsD daemon. ServiceCheck requires the Datadog tag format (see
+// TagsFormat) and, like Event, is never affected by SampleRate.
+func (c *Client) ServiceCheck(name string, status ServiceCheckStatus, opts ...ServiceCheckOption) {
+	if c.muted {
+		return
+	}
+	if status < OK || status > Unknown {
+		return
+	}
+
+	var s serviceCheck
+	for _, o := range opts {
+		o(&s)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "_sc|%s|%d", name, status)
+
+	if !s.timestamp.IsZero() {
+		fmt.Fprintf(&buf, "|d:%d", s.timestamp.Unix())
+	}
+	if s.hostname != "" {
+		fmt.Fprintf(&buf, "|h:%s", s.hostname)
+	}
+
+	buf.WriteString(joinTags(c.conn.tagFormat, mergeTags(c.tags, s.tags)))
+	buf.WriteString(c.originTag())
+
+	if s.message != "" {
+		buf.WriteString("|m:")
+		buf.WriteString(escapeServiceCheckMessage(s.message))
+	}
+	buf.WriteByte('\n')
+
+	c.conn.mu.Lock()
+	c.conn.write(buf.Bytes())
+	c.conn.mu.Unlock()
+
+	atomic.AddInt64(&c.conn.serviceChecksCount, 1)
+}
+
+// escapeServiceCheckMessage escapes newlines, pipes and any "m:" sequence
+// so the message, which is always the last field of the datagram, cannot
+// be mistaken for the start of another field.
+func escapeServiceCheckMessage(message string) string {
+	message = strings.Replace(message, "\n", `\n`, -1)
+	message = strings.Replace(message, "|", `\|`, -1)
+	message = strings.Replace(message, "m:", `m\:`, -1)
+	return message
+}
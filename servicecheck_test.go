@@ -0,0 +1,43 @@
+package statsd
+
+import "testing"
+
+func TestServiceCheckDatagram(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+
+	c.ServiceCheck("app.running", Warning,
+		ServiceCheckHostname("host"),
+		ServiceCheckTags("env", "prod"),
+		ServiceCheckMessage("disk at 90%"),
+	)
+	c.Flush()
+
+	want := "_sc|app.running|1|h:host|#env:prod|m:disk at 90%\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestServiceCheckIgnoresInvalidStatus(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+
+	c.ServiceCheck("app.running", ServiceCheckStatus(99))
+	c.Flush()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no datagram for invalid status, got %q", buf.String())
+	}
+}
+
+func TestEscapeServiceCheckMessage(t *testing.T) {
+	cases := map[string]string{
+		"line one\nline two": `line one\nline two`,
+		"a|b":                `a\|b`,
+		"m:danger":           `m\:danger`,
+	}
+	for in, want := range cases {
+		if got := escapeServiceCheckMessage(in); got != want {
+			t.Errorf("escapeServiceCheckMessage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
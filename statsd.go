@@ -4,12 +4,15 @@ import "time"
 
 // A Client represents a StatsD client.
 type Client struct {
-	conn      *conn
-	muted     bool
-	rate      float32
-	prefix    string
-	tagFormat TagFormat
-	tags      []tag
+	conn        *conn
+	muted       bool
+	rate        float32
+	prefix      string
+	tagFormat   TagFormat
+	tags        []tag
+	aggregator  *aggregator
+	containerID string
+	telemetry   *telemetry
 }
 
 // New returns a new Client.
@@ -44,6 +47,19 @@ func New(opts ...Option) (*Client, error) {
 	c.rate = conf.Client.Rate
 	c.prefix = conf.Client.Prefix
 	c.tags = conf.Client.Tags
+	if conf.Client.ContainerID != "" {
+		c.containerID = conf.Client.ContainerID
+	} else if conf.Client.OriginDetection {
+		c.containerID = detectContainerID()
+	}
+	if conf.Client.AggregationInterval > 0 {
+		c.aggregator = newAggregator(c)
+		go c.aggregator.run(conf.Client.AggregationInterval)
+	}
+	if conf.Client.TelemetryInterval > 0 {
+		c.telemetry = newTelemetry(c)
+		go c.telemetry.run(conf.Client.TelemetryInterval)
+	}
 	return c, nil
 }
 
@@ -65,11 +81,13 @@ func (c *Client) Clone(opts ...Option) *Client {
 	}
 
 	clone := &Client{
-		conn:   c.conn,
-		muted:  c.muted || conf.Client.Muted,
-		rate:   conf.Client.Rate,
-		prefix: conf.Client.Prefix,
-		tags:   conf.Client.Tags,
+		conn:        c.conn,
+		muted:       c.muted || conf.Client.Muted,
+		rate:        conf.Client.Rate,
+		prefix:      conf.Client.Prefix,
+		tags:        conf.Client.Tags,
+		aggregator:  c.aggregator,
+		containerID: c.containerID,
 	}
 	clone.conn = c.conn
 	return clone
@@ -80,7 +98,11 @@ func (c *Client) Count(bucket string, n interface{}, tags ...string) {
 	if c.skip() {
 		return
 	}
-	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags))
+	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags)) + c.originTag()
+	if c.aggregator != nil {
+		c.aggregator.count(c.prefix, bucket, scaleCount(n, c.rate), tagstr)
+		return
+	}
 	c.conn.metric(c.prefix, bucket, n, "c", c.rate, tagstr)
 }
 
@@ -88,6 +110,19 @@ func (c *Client) skip() bool {
 	return c.muted || (c.rate != 1 && randFloat() > c.rate)
 }
 
+// originTag returns the "|c:<container-id>" field appended to every
+// metric, event and service check sent by the Client, or "" if no
+// container ID was detected or configured (see OriginDetection and
+// ContainerID), or if the Client isn't using the Datadog tag format: like
+// the "|#tag:value" tags joinTags appends, "|c:" is a DogStatsD-specific
+// extension and has no place in a datagram meant for a plain StatsD daemon.
+func (c *Client) originTag() string {
+	if c.containerID == "" || c.conn.tagFormat != Datadog {
+		return ""
+	}
+	return "|c:" + c.containerID
+}
+
 // Increment increment the given bucket. It is equivalent to Count(bucket, 1).
 func (c *Client) Increment(bucket string) {
 	c.Count(bucket, 1)
@@ -98,7 +133,11 @@ func (c *Client) Gauge(bucket string, value interface{}, tags ...string) {
 	if c.skip() {
 		return
 	}
-	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags))
+	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags)) + c.originTag()
+	if c.aggregator != nil {
+		c.aggregator.gauge(c.prefix, bucket, value, tagstr)
+		return
+	}
 	c.conn.gauge(c.prefix, bucket, value, tagstr)
 }
 
@@ -107,7 +146,7 @@ func (c *Client) Timing(bucket string, value interface{}, tags ...string) {
 	if c.skip() {
 		return
 	}
-	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags))
+	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags)) + c.originTag()
 	c.conn.metric(c.prefix, bucket, value, "ms", c.rate, tagstr)
 }
 
@@ -116,10 +155,24 @@ func (c *Client) Histogram(bucket string, value interface{}, tags ...string) {
 	if c.skip() {
 		return
 	}
-	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags))
+	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags)) + c.originTag()
 	c.conn.metric(c.prefix, bucket, value, "h", c.rate, tagstr)
 }
 
+// Distribution sends a distribution value to a bucket. Unlike Histogram,
+// distributions are aggregated globally by the DogStatsD server rather than
+// on the host submitting them, which makes them suitable for computing
+// accurate percentiles across a fleet. Distribution requires the Datadog
+// tag format (see TagsFormat) and is otherwise ignored by StatsD daemons
+// that do not support it.
+func (c *Client) Distribution(bucket string, value interface{}, tags ...string) {
+	if c.skip() {
+		return
+	}
+	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags)) + c.originTag()
+	c.conn.metric(c.prefix, bucket, value, "d", c.rate, tagstr)
+}
+
 // A Timing is an helper object that eases sending timing values.
 type Timing struct {
 	start time.Time
@@ -146,15 +199,24 @@ func (c *Client) Unique(bucket string, value string, tags ...string) {
 	if c.skip() {
 		return
 	}
-	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags))
+	tagstr := joinTags(c.conn.tagFormat, mergeTags(c.tags, tags)) + c.originTag()
+	if c.aggregator != nil {
+		c.aggregator.unique(c.prefix, bucket, value, tagstr)
+		return
+	}
 	c.conn.unique(c.prefix, bucket, value, tagstr)
 }
 
-// Flush flushes the Client's buffer.
+// Flush flushes the Client's buffer, first draining any pending aggregated
+// metrics (see Aggregation).
 func (c *Client) Flush() {
 	if c.muted {
 		return
 	}
+	if c.aggregator != nil {
+		c.aggregator.flush()
+		return
+	}
 	c.conn.mu.Lock()
 	c.conn.flush(0)
 	c.conn.mu.Unlock()
@@ -166,6 +228,13 @@ func (c *Client) Close() {
 	if c.muted {
 		return
 	}
+	if c.aggregator != nil {
+		c.aggregator.stop <- struct{}{}
+		c.aggregator.flush()
+	}
+	if c.telemetry != nil {
+		c.telemetry.stop <- struct{}{}
+	}
 	c.conn.mu.Lock()
 	c.conn.flush(0)
 	c.conn.handleError(c.conn.w.Close())
@@ -0,0 +1,59 @@
+package statsd
+
+import "testing"
+
+func TestOriginTagRequiresDatadogTagFormat(t *testing.T) {
+	c, buf := newTestClient(InfluxDB)
+	c.containerID = "abc123"
+
+	c.Count("requests", 1)
+	c.Flush()
+
+	want := "requests:1|c\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDistributionDatagram(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+	c.tags = mergeTags(nil, []string{"env", "prod"})
+
+	c.Distribution("latency", 0.42, "route", "/login")
+	c.Flush()
+
+	want := "latency:0.42|d|#env:prod,route:/login\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDistributionAppliesSampleRate(t *testing.T) {
+	old := randFloat
+	randFloat = func() float32 { return 0 }
+	defer func() { randFloat = old }()
+
+	c, buf := newTestClient(Datadog)
+	c.rate = 0.5
+
+	c.Distribution("latency", 0.42)
+	c.Flush()
+
+	want := "latency:0.42|d|@0.5\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOriginTagWithDatadogTagFormat(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+	c.containerID = "abc123"
+
+	c.Count("requests", 1)
+	c.Flush()
+
+	want := "requests:1|c|c:abc123\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// version is reported in the client_version telemetry tag.
+const version = "1.0.0"
+
+type telemetrySnapshot struct {
+	metrics              int64
+	events               int64
+	serviceChecks        int64
+	bytesSent            int64
+	bytesDropped         int64
+	packetsSent          int64
+	packetsDroppedQueue  int64
+	packetsDroppedWriter int64
+}
+
+// A telemetry periodically reports counters describing a Client's own
+// behaviour through that Client's conn. See the Telemetry Option.
+type telemetry struct {
+	c    *Client
+	tags []string
+	prev telemetrySnapshot
+	stop chan struct{}
+}
+
+func newTelemetry(c *Client) *telemetry {
+	return &telemetry{
+		c: c,
+		tags: []string{
+			"client", "go",
+			"client_version", version,
+			"client_transport", c.conn.network,
+		},
+		stop: make(chan struct{}),
+	}
+}
+
+func (t *telemetry) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.flush()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+func (t *telemetry) snapshot() telemetrySnapshot {
+	conn := t.c.conn
+	return telemetrySnapshot{
+		metrics:              atomic.LoadInt64(&conn.metricsCount),
+		events:               atomic.LoadInt64(&conn.eventsCount),
+		serviceChecks:        atomic.LoadInt64(&conn.serviceChecksCount),
+		bytesSent:            atomic.LoadInt64(&conn.bytesSent),
+		bytesDropped:         atomic.LoadInt64(&conn.bytesDropped),
+		packetsSent:          atomic.LoadInt64(&conn.packetsSent),
+		packetsDroppedQueue:  atomic.LoadInt64(&conn.packetsDroppedQueue),
+		packetsDroppedWriter: atomic.LoadInt64(&conn.packetsDroppedWriter),
+	}
+}
+
+// flush emits the delta between the current and previous snapshot as
+// counters, then records the current snapshot as the new baseline.
+//
+// The counters are written directly to the conn, the way Event and
+// ServiceCheck do, rather than through Client.Count: going through Count
+// would itself bump the very counters telemetry is reporting on (and, with
+// Aggregation configured, fold telemetry's own counters into the
+// aggregator), so the client would never settle at a true steady state.
+func (t *telemetry) flush() {
+	cur := t.snapshot()
+	prev := t.prev
+
+	tagstr := joinTags(t.c.conn.tagFormat, mergeTags(nil, t.tags)) + t.c.originTag()
+
+	var buf bytes.Buffer
+	writeCounter := func(bucket string, n int64) {
+		buf.WriteString(t.c.prefix)
+		buf.WriteString(bucket)
+		buf.WriteByte(':')
+		fmt.Fprintf(&buf, "%d", n)
+		buf.WriteString("|c")
+		buf.WriteString(tagstr)
+		buf.WriteByte('\n')
+	}
+
+	writeCounter("datadog.dogstatsd.client.metrics", cur.metrics-prev.metrics)
+	writeCounter("datadog.dogstatsd.client.events", cur.events-prev.events)
+	writeCounter("datadog.dogstatsd.client.service_checks", cur.serviceChecks-prev.serviceChecks)
+	writeCounter("datadog.dogstatsd.client.bytes_sent", cur.bytesSent-prev.bytesSent)
+	writeCounter("datadog.dogstatsd.client.bytes_dropped", cur.bytesDropped-prev.bytesDropped)
+	writeCounter("datadog.dogstatsd.client.packets_sent", cur.packetsSent-prev.packetsSent)
+	writeCounter("datadog.dogstatsd.client.packets_dropped",
+		(cur.packetsDroppedQueue+cur.packetsDroppedWriter)-(prev.packetsDroppedQueue+prev.packetsDroppedWriter))
+	writeCounter("datadog.dogstatsd.client.packets_dropped_queue", cur.packetsDroppedQueue-prev.packetsDroppedQueue)
+	writeCounter("datadog.dogstatsd.client.packets_dropped_writer", cur.packetsDroppedWriter-prev.packetsDroppedWriter)
+
+	t.c.conn.mu.Lock()
+	t.c.conn.write(buf.Bytes())
+	t.c.conn.mu.Unlock()
+
+	t.prev = cur
+}
@@ -0,0 +1,39 @@
+package statsd
+
+import "testing"
+
+func TestTelemetryFlushReportsDelta(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+	c.telemetry = newTelemetry(c)
+
+	c.Count("requests", 1)
+	c.Flush()
+	buf.Reset()
+
+	c.telemetry.flush()
+	c.Flush()
+
+	want := "datadog.dogstatsd.client.metrics:1|c|#client:go,client_version:1.0.0,client_transport:\n"
+	if !containsLine(buf.String(), want) {
+		t.Errorf("missing metrics counter in telemetry flush output: %q", buf.String())
+	}
+}
+
+// TestTelemetryDoesNotCountItself guards against telemetry.flush reporting
+// its own previous submissions: a flush that goes through Client.Count
+// would bump the very counters it is reporting on, so a second flush with
+// no intervening user traffic would never settle at zero.
+func TestTelemetryDoesNotCountItself(t *testing.T) {
+	c, buf := newTestClient(Datadog)
+	c.telemetry = newTelemetry(c)
+
+	c.telemetry.flush()
+	buf.Reset()
+
+	c.telemetry.flush()
+
+	want := "datadog.dogstatsd.client.metrics:0|c|#client:go,client_version:1.0.0,client_transport:\n"
+	if !containsLine(buf.String(), want) {
+		t.Errorf("telemetry counted its own submissions; got %q", buf.String())
+	}
+}
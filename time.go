@@ -0,0 +1,6 @@
+package statsd
+
+import "time"
+
+// now is a variable so that it can be overridden in tests.
+var now = time.Now